@@ -0,0 +1,68 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+// DefaultConfidenceThreshold is the default confidence level for text classification.
+const DefaultConfidenceThreshold = 0.80
+
+// Classifier provides methods for identifying open source licenses in text content.
+type Classifier struct {
+	Threshold float64
+	Dict      *Dictionary
+	Docs      map[string]*IndexedDocument
+	Q         int
+
+	// tokenizer, when non-nil, is used instead of the package-level tokenize
+	// function to turn raw content into a document. See WithTokenizer.
+	tokenizer Tokenizer
+
+	// ngramIndex is the global q-gram inverted index used by
+	// CandidateDocuments to shortlist corpus documents worth aligning
+	// against, instead of scanning all of Docs. See WithMinCandidateOverlap
+	// and WithMaxCandidates.
+	ngramIndex          map[uint32][]posting
+	docNgrams           map[string][]uint32
+	minCandidateOverlap int
+	maxCandidates       int
+}
+
+// defaultMaxCandidates is the CandidateDocuments cap used when NewClassifier
+// is not given WithMaxCandidates. There is no equivalent flat default for
+// minCandidateOverlap: left at its zero value, CandidateDocuments derives it
+// per call from c.Q, c.Threshold, and the target's q-gram count instead. See
+// WithMinCandidateOverlap.
+const defaultMaxCandidates = 50
+
+// Option configures optional behavior of a Classifier. Options are applied
+// in order by NewClassifier.
+type Option func(*Classifier)
+
+// NewClassifier creates a new classifier that recognizes content matching at
+// or above the given confidence threshold.
+func NewClassifier(threshold float64, opts ...Option) *Classifier {
+	c := &Classifier{
+		Threshold:     threshold,
+		Dict:          newDictionary(),
+		Docs:          make(map[string]*IndexedDocument),
+		Q:             computeQ(threshold),
+		ngramIndex:    make(map[uint32][]posting),
+		docNgrams:     make(map[string][]uint32),
+		maxCandidates: defaultMaxCandidates,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
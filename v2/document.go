@@ -49,6 +49,13 @@ type IndexedDocument struct {
 	s      *SearchSet      // The searchset for this document
 	runes  []rune
 	norm   string // The normalized token sequence
+
+	// raw and srcTokens retain the original source bytes and pre-indexing
+	// tokens for a target document, so that a Match produced against it can
+	// later reconstruct a Snippet/Positions. They are left nil for corpus
+	// documents, which never appear as the target of a match.
+	raw       []byte
+	srcTokens []*token
 }
 
 func (d *IndexedDocument) generateSearchSet(q int) {
@@ -101,7 +108,7 @@ func max(a, b int) int {
 // AddContent incorporates the provided textual content into the classifier for
 // matching. This will not modify the supplied content.
 func (c *Classifier) AddContent(category, name, variant string, content []byte) {
-	doc := tokenize(content)
+	doc := c.tokenizeContent(content)
 	c.addDocument(category, name, variant, doc)
 }
 
@@ -116,6 +123,7 @@ func (c *Classifier) addDocument(category, name, variant string, doc *document)
 	id.generateSearchSet(c.Q)
 	id.s.origin = indexName
 	c.Docs[indexName] = id
+	c.indexDocumentNgrams(indexName, id)
 }
 
 // generateIndexedDocument creates an IndexedDocument from the supplied document. if addWords
@@ -151,8 +159,11 @@ func (c *Classifier) generateIndexedDocument(d *document, addWords bool) *Indexe
 // words to the classifier dictionary. This should be used for matching targets, not
 // populating the corpus.
 func (c *Classifier) createTargetIndexedDocument(in []byte) *IndexedDocument {
-	doc := tokenize(in)
-	return c.generateIndexedDocument(doc, false)
+	doc := c.tokenizeContent(in)
+	id := c.generateIndexedDocument(doc, false)
+	id.raw = in
+	id.srcTokens = doc.Tokens
+	return id
 }
 
 func (c *Classifier) generateDocName(category, name, variant string) string {
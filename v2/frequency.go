@@ -0,0 +1,66 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// FrequencyTable records how many times each tokenID occurs within a single
+// document. It is used to cheaply estimate a candidate document's token
+// overlap with a target before paying for a full alignment.
+type FrequencyTable struct {
+	counts map[tokenID]int
+}
+
+func newFrequencyTable() *FrequencyTable {
+	return &FrequencyTable{counts: make(map[tokenID]int)}
+}
+
+// count returns how many times id occurs in the document this table was
+// computed for.
+func (f *FrequencyTable) count(id tokenID) int {
+	if f == nil {
+		return 0
+	}
+	return f.counts[id]
+}
+
+// generateFrequencies (re)computes d's FrequencyTable from its current
+// Tokens.
+func (d *IndexedDocument) generateFrequencies() {
+	f := newFrequencyTable()
+	for _, t := range d.Tokens {
+		f.counts[t.ID]++
+	}
+	d.f = f
+}
+
+// GobEncode and GobDecode let FrequencyTable round-trip through gob despite
+// its only field, counts, being unexported - gob otherwise silently skips
+// unexported fields rather than erroring, which would make a loaded
+// FrequencyTable come back empty.
+func (f *FrequencyTable) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(f.counts); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (f *FrequencyTable) GobDecode(data []byte) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(&f.counts)
+}
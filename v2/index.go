@@ -0,0 +1,168 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"hash/crc32"
+	"io"
+)
+
+// indexSchemaVersion is incremented whenever the on-disk layout written by
+// SaveIndex changes in a way that LoadIndex cannot read transparently.
+const indexSchemaVersion = 1
+
+// indexHeader precedes the gob-encoded payload in a saved index. It lets
+// LoadIndex reject files written by an incompatible version of this package,
+// or built with a different q-gram threshold, before it touches the
+// (potentially large) payload that follows.
+type indexHeader struct {
+	Version uint32
+	Q       int
+	Size    int64
+	CRC32   uint32
+}
+
+// indexedDocumentSnapshot captures everything needed to reconstitute an
+// IndexedDocument without re-tokenizing or re-deriving its search data.
+type indexedDocumentSnapshot struct {
+	Name   string
+	Tokens []indexedToken
+	Runes  []rune
+	Norm   string
+	Freq   *FrequencyTable
+	Search *SearchSet
+}
+
+// indexSnapshot is the full gob payload written by SaveIndex.
+type indexSnapshot struct {
+	Dict *Dictionary
+	Docs []indexedDocumentSnapshot
+}
+
+// SaveIndex writes a versioned, checksummed binary snapshot of the
+// classifier's corpus - its Dictionary together with every IndexedDocument's
+// Tokens, runes, normalized form, FrequencyTable, and SearchSet - to w. The
+// resulting snapshot can be restored with LoadIndex, which is dramatically
+// cheaper than rebuilding the corpus from raw license text.
+func (c *Classifier) SaveIndex(w io.Writer) error {
+	snap := indexSnapshot{
+		Dict: c.Dict,
+		Docs: make([]indexedDocumentSnapshot, 0, len(c.Docs)),
+	}
+	for name, doc := range c.Docs {
+		snap.Docs = append(snap.Docs, indexedDocumentSnapshot{
+			Name:   name,
+			Tokens: doc.Tokens,
+			Runes:  doc.runes,
+			Norm:   doc.norm,
+			Freq:   doc.f,
+			Search: doc.s,
+		})
+	}
+
+	var payload bytes.Buffer
+	if err := gob.NewEncoder(&payload).Encode(snap); err != nil {
+		return fmt.Errorf("encoding classifier index: %w", err)
+	}
+
+	hdr := indexHeader{
+		Version: indexSchemaVersion,
+		Q:       c.Q,
+		Size:    int64(payload.Len()),
+		CRC32:   crc32.ChecksumIEEE(payload.Bytes()),
+	}
+	// Both values are written through the same Encoder, and LoadIndex reads
+	// them back through the same Decoder. gob.Decoder may read ahead of the
+	// header into its own internal buffer, so writing the payload as a raw
+	// io.Writer.Write after an independent gob.Encoder.Encode(hdr) would risk
+	// the subsequent raw read missing bytes the decoder already buffered.
+	enc := gob.NewEncoder(w)
+	if err := enc.Encode(hdr); err != nil {
+		return fmt.Errorf("writing classifier index header: %w", err)
+	}
+	if err := enc.Encode(payload.Bytes()); err != nil {
+		return fmt.Errorf("writing classifier index payload: %w", err)
+	}
+	return nil
+}
+
+// LoadIndex reconstructs a Classifier from a snapshot written by SaveIndex.
+// If the snapshot's schema version is not one this package understands, or
+// its Q was computed from a different confidence threshold than the caller
+// needs, LoadIndex returns errIndexIncompatible so the caller can fall back
+// to rebuilding the corpus from source instead of running bad matches. opts
+// are applied the same way as in NewClassifier, so callers can attach a
+// WithTokenizer or override the CandidateDocuments knobs on the restored
+// Classifier.
+func LoadIndex(r io.Reader, threshold float64, opts ...Option) (*Classifier, error) {
+	var hdr indexHeader
+	dec := gob.NewDecoder(r)
+	if err := dec.Decode(&hdr); err != nil {
+		return nil, fmt.Errorf("reading classifier index header: %w", err)
+	}
+	if hdr.Version != indexSchemaVersion {
+		return nil, fmt.Errorf("%w: got schema version %d, want %d", errIndexIncompatible, hdr.Version, indexSchemaVersion)
+	}
+	if q := computeQ(threshold); q != hdr.Q {
+		return nil, fmt.Errorf("%w: index was built with Q=%d, threshold %v requires Q=%d", errIndexIncompatible, hdr.Q, threshold, q)
+	}
+
+	var payload []byte
+	if err := dec.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("reading classifier index payload: %w", err)
+	}
+	if int64(len(payload)) != hdr.Size {
+		return nil, fmt.Errorf("classifier index payload size mismatch: got %d bytes, want %d", len(payload), hdr.Size)
+	}
+	if got := crc32.ChecksumIEEE(payload); got != hdr.CRC32 {
+		return nil, fmt.Errorf("classifier index payload failed checksum: got %08x, want %08x", got, hdr.CRC32)
+	}
+
+	var snap indexSnapshot
+	if err := gob.NewDecoder(bytes.NewReader(payload)).Decode(&snap); err != nil {
+		return nil, fmt.Errorf("decoding classifier index: %w", err)
+	}
+
+	c := &Classifier{
+		Threshold:     threshold,
+		Dict:          snap.Dict,
+		Docs:          make(map[string]*IndexedDocument, len(snap.Docs)),
+		Q:             hdr.Q,
+		ngramIndex:    make(map[uint32][]posting),
+		maxCandidates: defaultMaxCandidates,
+	}
+	for _, ds := range snap.Docs {
+		c.Docs[ds.Name] = &IndexedDocument{
+			Tokens: ds.Tokens,
+			dict:   c.Dict,
+			f:      ds.Freq,
+			s:      ds.Search,
+			runes:  ds.Runes,
+			norm:   ds.Norm,
+		}
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.rebuildNgramIndex()
+	return c, nil
+}
+
+// errIndexIncompatible is returned by LoadIndex when a saved snapshot cannot
+// be used as-is and the corpus must be rebuilt instead.
+var errIndexIncompatible = fmt.Errorf("classifier index is incompatible")
@@ -0,0 +1,135 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func newTestClassifier(t *testing.T) *Classifier {
+	t.Helper()
+	c := NewClassifier(DefaultConfidenceThreshold)
+	c.AddContent("license", "MIT", "MIT", []byte("Permission is hereby granted, free of charge, to any person obtaining a copy"))
+	c.AddContent("license", "Apache-2.0", "Apache-2.0", []byte("Licensed under the Apache License, Version 2.0"))
+	return c
+}
+
+// TestSaveLoadIndexRoundTrip exercises SaveIndex/LoadIndex through an
+// in-memory buffer, where every read is available immediately.
+func TestSaveLoadIndexRoundTrip(t *testing.T) {
+	c := newTestClassifier(t)
+
+	var buf bytes.Buffer
+	if err := c.SaveIndex(&buf); err != nil {
+		t.Fatalf("SaveIndex: %v", err)
+	}
+
+	loaded, err := LoadIndex(&buf, DefaultConfidenceThreshold)
+	if err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	if len(loaded.Docs) != len(c.Docs) {
+		t.Fatalf("LoadIndex: got %d docs, want %d", len(loaded.Docs), len(c.Docs))
+	}
+	for name, doc := range c.Docs {
+		got, ok := loaded.Docs[name]
+		if !ok {
+			t.Fatalf("LoadIndex: missing document %q", name)
+		}
+		if got.norm != doc.norm {
+			t.Errorf("LoadIndex: document %q norm = %q, want %q", name, got.norm, doc.norm)
+		}
+		for _, tok := range doc.Tokens {
+			if got.f.count(tok.ID) != doc.f.count(tok.ID) {
+				t.Errorf("LoadIndex: document %q FrequencyTable did not survive the round trip for token %d", name, tok.ID)
+			}
+		}
+		if got.s == nil || len(got.s.hashes) != len(doc.s.hashes) {
+			t.Errorf("LoadIndex: document %q SearchSet hashes did not survive the round trip", name)
+		}
+		if got.s == nil || got.s.origin != doc.s.origin {
+			t.Errorf("LoadIndex: document %q SearchSet.origin = %q, want %q", name, got.s.origin, doc.s.origin)
+		}
+	}
+
+	// CandidateDocuments relies entirely on data restored by LoadIndex (the
+	// ngram index is rebuilt from loaded.Docs' Tokens, and the target is
+	// indexed against the loaded Dictionary), so exercising it here checks
+	// that those round-tripped fields are usable, not just present.
+	target := loaded.createTargetIndexedDocument([]byte("Permission is hereby granted, free of charge, to any person obtaining a copy"))
+	candidates := loaded.CandidateDocuments(target)
+	want := loaded.generateDocName("license", "MIT", "MIT")
+	var found bool
+	for _, name := range candidates {
+		if name == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("CandidateDocuments on loaded classifier: %v does not contain %q", candidates, want)
+	}
+}
+
+// TestSaveLoadIndexFileRoundTrip exercises SaveIndex/LoadIndex through a real
+// *os.File, which unlike a bytes.Buffer does not implement io.ByteReader.
+// gob.NewDecoder wraps such readers in its own buffered reader internally;
+// LoadIndex must do all of its reading through that one Decoder so it never
+// races the decoder's read-ahead by also reading the file directly.
+func TestSaveLoadIndexFileRoundTrip(t *testing.T) {
+	c := newTestClassifier(t)
+
+	path := filepath.Join(t.TempDir(), "index.gob")
+	f, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("os.Create: %v", err)
+	}
+	if err := c.SaveIndex(f); err != nil {
+		f.Close()
+		t.Fatalf("SaveIndex: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("closing index file: %v", err)
+	}
+
+	rf, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("os.Open: %v", err)
+	}
+	defer rf.Close()
+
+	loaded, err := LoadIndex(rf, DefaultConfidenceThreshold)
+	if err != nil {
+		t.Fatalf("LoadIndex: %v", err)
+	}
+	if len(loaded.Docs) != len(c.Docs) {
+		t.Fatalf("LoadIndex: got %d docs, want %d", len(loaded.Docs), len(c.Docs))
+	}
+}
+
+func TestLoadIndexRejectsMismatchedThreshold(t *testing.T) {
+	c := newTestClassifier(t)
+
+	var buf bytes.Buffer
+	if err := c.SaveIndex(&buf); err != nil {
+		t.Fatalf("SaveIndex: %v", err)
+	}
+
+	if _, err := LoadIndex(&buf, 0.5); err == nil {
+		t.Fatal("LoadIndex: want error for a threshold that requires a different Q, got nil")
+	}
+}
@@ -0,0 +1,26 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+// Match describes a single license identified within a target document,
+// together with the span of the target's tokens that produced it.
+type Match struct {
+	Name       string  // name of the recognized license
+	Confidence float64 // confidence level of this match, between 0 and 1
+
+	target          *IndexedDocument // the document the match was found in
+	startTokenIndex int              // index into target.Tokens where the match begins
+	endTokenIndex   int              // index into target.Tokens, exclusive, where the match ends
+}
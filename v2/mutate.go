@@ -0,0 +1,81 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+// RemoveContent removes the named license variant from the classifier's
+// corpus, if present. It reports whether a document was removed. The
+// Dictionary is left untouched - tokenIDs belonging only to the removed
+// document become unreferenced but are not reclaimed until Compact is
+// called, so callers that plan a batch of removals and additions should
+// Compact once at the end rather than after every call.
+func (c *Classifier) RemoveContent(category, name, variant string) bool {
+	indexName := c.generateDocName(category, name, variant)
+	if _, ok := c.Docs[indexName]; !ok {
+		return false
+	}
+	delete(c.Docs, indexName)
+	c.removeDocumentNgrams(indexName)
+	return true
+}
+
+// ReplaceContent replaces the named license variant's content, re-tokenizing
+// and re-indexing it in place. If the variant does not already exist,
+// ReplaceContent adds it, behaving like AddContent.
+func (c *Classifier) ReplaceContent(category, name, variant string, content []byte) {
+	c.RemoveContent(category, name, variant)
+	c.AddContent(category, name, variant, content)
+}
+
+// Compact rewrites the classifier's Dictionary to a dense tokenID space
+// containing only words still referenced by a document in c.Docs, and
+// remaps every remaining IndexedDocument's Tokens, runes, normalized form,
+// FrequencyTable, and SearchSet accordingly. It returns a map from each
+// surviving word's old tokenID to its new one, so callers holding external
+// references to tokenIDs (e.g. ids cached from an earlier LoadIndex) can
+// migrate them.
+//
+// Compact is a prerequisite for long-running services that hot-reload
+// license definitions via RemoveContent/ReplaceContent: without it, the
+// Dictionary only ever grows, even as variants are removed.
+func (c *Classifier) Compact() map[tokenID]tokenID {
+	newDict := newDictionary()
+	remap := make(map[tokenID]tokenID)
+
+	for _, doc := range c.Docs {
+		for _, t := range doc.Tokens {
+			if _, ok := remap[t.ID]; ok {
+				continue
+			}
+			word := c.Dict.getWord(t.ID)
+			remap[t.ID] = newDict.add(word)
+		}
+	}
+
+	for indexName, doc := range c.Docs {
+		for i, t := range doc.Tokens {
+			doc.Tokens[i].ID = remap[t.ID]
+		}
+		doc.dict = newDict
+		doc.generateFrequencies()
+		doc.runes = diffWordsToRunes(doc, 0, doc.size())
+		doc.norm = doc.normalized()
+		doc.generateSearchSet(c.Q)
+		doc.s.origin = indexName
+	}
+
+	c.Dict = newDict
+	c.rebuildNgramIndex()
+	return remap
+}
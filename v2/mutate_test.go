@@ -0,0 +1,76 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import "testing"
+
+func TestRemoveContent(t *testing.T) {
+	c := newTestClassifier(t)
+
+	if ok := c.RemoveContent("license", "MIT", "MIT"); !ok {
+		t.Fatal("RemoveContent: want true for an existing variant")
+	}
+	if _, ok := c.Docs[c.generateDocName("license", "MIT", "MIT")]; ok {
+		t.Fatal("RemoveContent: document still present after removal")
+	}
+	if ok := c.RemoveContent("license", "MIT", "MIT"); ok {
+		t.Fatal("RemoveContent: want false for an already-removed variant")
+	}
+}
+
+func TestReplaceContent(t *testing.T) {
+	c := newTestClassifier(t)
+	name := c.generateDocName("license", "MIT", "MIT")
+	before := c.Docs[name]
+
+	c.ReplaceContent("license", "MIT", "MIT", []byte("a completely different body of text entirely"))
+
+	after, ok := c.Docs[name]
+	if !ok {
+		t.Fatal("ReplaceContent: document missing after replace")
+	}
+	if after == before {
+		t.Fatal("ReplaceContent: want a freshly indexed document, got the same pointer")
+	}
+	if after.norm == before.norm {
+		t.Fatal("ReplaceContent: normalized content unchanged after replace")
+	}
+}
+
+func TestCompactRemapsTokenIDsAndShrinksDictionary(t *testing.T) {
+	c := newTestClassifier(t)
+	c.RemoveContent("license", "Apache-2.0", "Apache-2.0")
+	sizeBefore := len(c.Dict.Words)
+
+	remap := c.Compact()
+
+	if len(c.Dict.Words) >= sizeBefore {
+		t.Fatalf("Compact: dictionary size %d, want less than %d after removing a variant", len(c.Dict.Words), sizeBefore)
+	}
+	for name, doc := range c.Docs {
+		for _, tok := range doc.Tokens {
+			word := c.Dict.getWord(tok.ID)
+			if word == unknownWord {
+				t.Fatalf("Compact: document %q has a token ID with no word in the compacted dictionary", name)
+			}
+		}
+		if doc.dict != c.Dict {
+			t.Fatalf("Compact: document %q still points at the old dictionary", name)
+		}
+	}
+	if len(remap) == 0 {
+		t.Fatal("Compact: want a non-empty old-to-new tokenID map")
+	}
+}
@@ -0,0 +1,188 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import (
+	"math"
+	"sort"
+)
+
+// posting is one occurrence of a q-gram within a corpus document, recorded
+// in the classifier's global ngramIndex.
+type posting struct {
+	Doc    string // name of the document the q-gram occurs in, as in c.Docs
+	Offset int    // token offset within that document's Tokens
+}
+
+// WithMinCandidateOverlap sets the minimum number of shared q-grams a
+// corpus document must have with a target before CandidateDocuments
+// considers it a candidate, overriding the threshold CandidateDocuments
+// would otherwise derive from c.Q, c.Threshold, and the target's size.
+// Raising it trades recall for latency by shrinking the shortlist passed on
+// to alignment. A value below 1 leaves CandidateDocuments using the derived
+// threshold.
+func WithMinCandidateOverlap(n int) Option {
+	return func(c *Classifier) {
+		c.minCandidateOverlap = n
+	}
+}
+
+// autoMinCandidateOverlap derives the default coverage threshold used by
+// CandidateDocuments when WithMinCandidateOverlap was not given. A target
+// with numIDs tokens contains numIDs-q+1 overlapping q-grams; at confidence
+// threshold T, a genuine match is expected to agree on roughly T of them, so
+// a corpus document is only worth the cost of full alignment if it shares
+// at least that many.
+func autoMinCandidateOverlap(q int, threshold float64, numIDs int) int {
+	numQgrams := numIDs - q + 1
+	if numQgrams < 1 {
+		numQgrams = 1
+	}
+	n := int(math.Ceil(threshold * float64(numQgrams)))
+	if n < 1 {
+		n = 1
+	}
+	return n
+}
+
+// WithMaxCandidates caps the number of documents CandidateDocuments returns,
+// keeping the highest-overlap ones. A value of 0 means unlimited.
+func WithMaxCandidates(n int) Option {
+	return func(c *Classifier) {
+		c.maxCandidates = n
+	}
+}
+
+// hashNgram computes an FNV-1a hash over a run of q-gram token ids, reusing
+// the same notion of a q-gram run as SearchSet.
+func hashNgram(ids []tokenID) uint32 {
+	var h uint32 = 2166136261
+	for _, id := range ids {
+		h ^= uint32(id)
+		h *= 16777619
+	}
+	return h
+}
+
+// indexDocumentNgrams adds every q-gram occurring in doc to the classifier's
+// global inverted index under the given document name, and records the set
+// of hashes it touched in docNgrams so removeDocumentNgrams can later undo
+// exactly this work without rescanning the rest of the corpus.
+func (c *Classifier) indexDocumentNgrams(name string, doc *IndexedDocument) {
+	if c.ngramIndex == nil {
+		c.ngramIndex = make(map[uint32][]posting)
+	}
+	if c.docNgrams == nil {
+		c.docNgrams = make(map[string][]uint32)
+	}
+	ids := tokenIDs(doc)
+	var seen map[uint32]bool
+	for offset := 0; offset+c.Q <= len(ids); offset++ {
+		h := hashNgram(ids[offset : offset+c.Q])
+		c.ngramIndex[h] = append(c.ngramIndex[h], posting{Doc: name, Offset: offset})
+		if seen == nil {
+			seen = make(map[uint32]bool)
+		}
+		if !seen[h] {
+			seen[h] = true
+			c.docNgrams[name] = append(c.docNgrams[name], h)
+		}
+	}
+}
+
+// removeDocumentNgrams undoes indexDocumentNgrams for name, deleting just
+// its postings from each q-gram bucket it appears in rather than rebuilding
+// the whole index. This keeps RemoveContent/ReplaceContent cheap (proportional
+// to the removed document's size) even when called repeatedly against a
+// corpus of thousands of variants.
+func (c *Classifier) removeDocumentNgrams(name string) {
+	for _, h := range c.docNgrams[name] {
+		postings := c.ngramIndex[h][:0]
+		for _, p := range c.ngramIndex[h] {
+			if p.Doc != name {
+				postings = append(postings, p)
+			}
+		}
+		if len(postings) == 0 {
+			delete(c.ngramIndex, h)
+		} else {
+			c.ngramIndex[h] = postings
+		}
+	}
+	delete(c.docNgrams, name)
+}
+
+// rebuildNgramIndex recomputes the global inverted index from scratch. It is
+// used after Compact, which remaps every surviving document's tokenIDs (and
+// so every q-gram hash) in one pass, making an incremental update no cheaper
+// than starting over.
+func (c *Classifier) rebuildNgramIndex() {
+	c.ngramIndex = make(map[uint32][]posting)
+	c.docNgrams = make(map[string][]uint32)
+	for name, doc := range c.Docs {
+		c.indexDocumentNgrams(name, doc)
+	}
+}
+
+func tokenIDs(doc *IndexedDocument) []tokenID {
+	ids := make([]tokenID, len(doc.Tokens))
+	for i, t := range doc.Tokens {
+		ids[i] = t.ID
+	}
+	return ids
+}
+
+// CandidateDocuments returns the names of corpus documents worth running
+// full alignment against for target, using the classifier's global q-gram
+// inverted index to avoid scanning every entry in c.Docs. It hashes
+// target's q-grams once, tallies how many postings each corpus document
+// matches, keeps those meeting the minimum overlap (see
+// WithMinCandidateOverlap), and returns them ordered by descending overlap,
+// capped as set by WithMaxCandidates.
+func (c *Classifier) CandidateDocuments(target *IndexedDocument) []string {
+	if c.ngramIndex == nil {
+		c.rebuildNgramIndex()
+	}
+	ids := tokenIDs(target)
+	if len(ids) < c.Q {
+		return nil
+	}
+
+	counts := make(map[string]int)
+	for offset := 0; offset+c.Q <= len(ids); offset++ {
+		h := hashNgram(ids[offset : offset+c.Q])
+		for _, p := range c.ngramIndex[h] {
+			counts[p.Doc]++
+		}
+	}
+
+	minOverlap := c.minCandidateOverlap
+	if minOverlap < 1 {
+		minOverlap = autoMinCandidateOverlap(c.Q, c.Threshold, len(ids))
+	}
+	candidates := make([]string, 0, len(counts))
+	for doc, n := range counts {
+		if n >= minOverlap {
+			candidates = append(candidates, doc)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return counts[candidates[i]] > counts[candidates[j]]
+	})
+	if max := c.maxCandidates; max > 0 && len(candidates) > max {
+		candidates = candidates[:max]
+	}
+	return candidates
+}
@@ -0,0 +1,67 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import "testing"
+
+func TestCandidateDocumentsFindsOverlappingCorpusDoc(t *testing.T) {
+	c := newTestClassifier(t)
+	target := c.createTargetIndexedDocument([]byte("Permission is hereby granted, free of charge, to any person obtaining a copy"))
+
+	candidates := c.CandidateDocuments(target)
+	if len(candidates) == 0 {
+		t.Fatal("CandidateDocuments: want at least one candidate for a near-identical target")
+	}
+
+	want := c.generateDocName("license", "MIT", "MIT")
+	var found bool
+	for _, name := range candidates {
+		if name == want {
+			found = true
+		}
+	}
+	if !found {
+		t.Errorf("CandidateDocuments: %v does not contain %q", candidates, want)
+	}
+}
+
+func TestCandidateDocumentsRespectsMaxCandidates(t *testing.T) {
+	c := NewClassifier(DefaultConfidenceThreshold, WithMaxCandidates(1))
+	c.AddContent("license", "A", "A", []byte("the quick brown fox jumps over the lazy dog"))
+	c.AddContent("license", "B", "B", []byte("the quick brown fox jumps over the lazy cat"))
+
+	target := c.createTargetIndexedDocument([]byte("the quick brown fox jumps over the lazy dog"))
+	if got := c.CandidateDocuments(target); len(got) > 1 {
+		t.Errorf("CandidateDocuments: got %d candidates, want at most 1", len(got))
+	}
+}
+
+func TestRemoveContentDropsItsNgramPostings(t *testing.T) {
+	c := newTestClassifier(t)
+	mitName := c.generateDocName("license", "MIT", "MIT")
+
+	c.RemoveContent("license", "MIT", "MIT")
+
+	for h, postings := range c.ngramIndex {
+		for _, p := range postings {
+			if p.Doc == mitName {
+				t.Fatalf("RemoveContent: ngramIndex[%d] still references removed document %q", h, mitName)
+			}
+		}
+	}
+	if _, ok := c.docNgrams[mitName]; ok {
+		t.Errorf("RemoveContent: docNgrams still tracks removed document %q", mitName)
+	}
+}
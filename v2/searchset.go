@@ -0,0 +1,70 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+// SearchSet holds a per-document q-gram hash index, built with the same
+// q-gram definition as the classifier's global ngramIndex (see
+// ngramindex.go), used to cheaply compare one document against another
+// without a full alignment.
+type SearchSet struct {
+	hashes map[uint32][]int // q-gram hash -> token offsets within the document
+	origin string           // name of the corpus document this set was built for, if any
+}
+
+// newSearchSet computes the q-gram hash index for d using q-gram length q.
+func newSearchSet(d *IndexedDocument, q int) *SearchSet {
+	s := &SearchSet{hashes: make(map[uint32][]int)}
+	ids := tokenIDs(d)
+	for offset := 0; offset+q <= len(ids); offset++ {
+		h := hashNgram(ids[offset : offset+q])
+		s.hashes[h] = append(s.hashes[h], offset)
+	}
+	return s
+}
+
+// gobSearchSet mirrors SearchSet with exported fields, purely so gob has
+// something it can encode; SearchSet itself keeps its fields unexported.
+type gobSearchSet struct {
+	Hashes map[uint32][]int
+	Origin string
+}
+
+// GobEncode and GobDecode let SearchSet round-trip through gob despite its
+// fields being unexported - gob otherwise silently skips unexported fields
+// rather than erroring, which would make a loaded SearchSet (and the origin
+// set on it by addDocument) come back empty.
+func (s *SearchSet) GobEncode() ([]byte, error) {
+	var buf bytes.Buffer
+	g := gobSearchSet{Hashes: s.hashes, Origin: s.origin}
+	if err := gob.NewEncoder(&buf).Encode(g); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (s *SearchSet) GobDecode(data []byte) error {
+	var g gobSearchSet
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&g); err != nil {
+		return err
+	}
+	s.hashes = g.Hashes
+	s.origin = g.Origin
+	return nil
+}
@@ -0,0 +1,165 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import (
+	"bytes"
+	"strings"
+)
+
+// Range identifies a span of the original, unmodified source text that
+// produced part of a Match, in 1-based line/column coordinates.
+type Range struct {
+	StartLine, StartCol int
+	EndLine, EndCol     int
+}
+
+// Positions reports where in the target's original source text this match
+// was found, derived from the target document's tokens. It returns an empty
+// slice if the match was produced from tokens supplied directly via
+// AddContentTokens/MatchTokens, which carry no source position information.
+func (m *Match) Positions() []Range {
+	toks := m.target.srcTokens
+	if toks == nil || m.target.raw == nil || m.startTokenIndex >= m.endTokenIndex || m.endTokenIndex > len(toks) {
+		return nil
+	}
+	first, last := toks[m.startTokenIndex], toks[m.endTokenIndex-1]
+	offsets, lineEnd := tokenByteOffsets(m.target.raw, toks)
+	end := tokenEndOffset(m.target.raw, lineEnd, toks, offsets, m.endTokenIndex-1)
+	return []Range{{
+		StartLine: first.Line,
+		StartCol:  runeColumn(m.target.raw, offsets[m.startTokenIndex]),
+		EndLine:   last.Line,
+		EndCol:    runeColumn(m.target.raw, end),
+	}}
+}
+
+// Snippet returns the original (non-normalized) source text of the match,
+// padded with up to radius bytes of surrounding context on each side. It
+// returns the empty string under the same conditions as Positions.
+func (m *Match) Snippet(radius int) string {
+	toks := m.target.srcTokens
+	if toks == nil || m.target.raw == nil || m.startTokenIndex >= m.endTokenIndex || m.endTokenIndex > len(toks) {
+		return ""
+	}
+	offsets, lineEnd := tokenByteOffsets(m.target.raw, toks)
+
+	start := offsets[m.startTokenIndex] - radius
+	if start < 0 {
+		start = 0
+	}
+	end := tokenEndOffset(m.target.raw, lineEnd, toks, offsets, m.endTokenIndex-1) + radius
+	if end > len(m.target.raw) {
+		end = len(m.target.raw)
+	}
+	return string(m.target.raw[start:end])
+}
+
+// lineStarts returns the byte offset of the start of each line in raw, 1
+// indexed so that lineStarts[line-1] is the start of that line and
+// lineStarts[line] (or len(raw), for the last line) is its end.
+func lineStarts(raw []byte) []int {
+	starts := []int{0}
+	for i, b := range raw {
+		if b == '\n' {
+			starts = append(starts, i+1)
+		}
+	}
+	return starts
+}
+
+// tokenByteOffsets locates the byte offset where each token's text starts
+// within raw, and returns alongside it a lineEnd helper (the byte offset one
+// past the end of a given line, excluding its trailing newline) that callers
+// use to work out where a token's span ends. The first token on a line
+// carries its leading text in Previous, so the search for it starts
+// len(Previous) bytes into its Line rather than at the line's start. Later
+// tokens on the same line are searched for after the previous token's end.
+// Every search is bounded to the remainder of the token's line and is
+// case-insensitive, since Text is normalized. A token whose normalized
+// text cannot be found in the line (e.g. because normalization stripped
+// punctuation a custom Tokenizer emitted) only affects later tokens on that
+// same line - the next line resyncs from its own Previous - rather than
+// drifting every later token in the document.
+func tokenByteOffsets(raw []byte, tokens []*token) (offsets []int, lineEnd func(line int) int) {
+	starts := lineStarts(raw)
+	lower := bytes.ToLower(raw)
+	offsets = make([]int, len(tokens))
+
+	lineEnd = func(line int) int {
+		end := len(raw)
+		if line < len(starts) {
+			end = starts[line]
+		}
+		for end > 0 && (raw[end-1] == '\n' || raw[end-1] == '\r') {
+			end--
+		}
+		return end
+	}
+
+	lastLine := 0
+	lineCursor := 0 // next unsearched byte offset within the current line
+	for i, t := range tokens {
+		start := 0
+		if t.Line-1 >= 0 && t.Line-1 < len(starts) {
+			start = starts[t.Line-1]
+		}
+		end := lineEnd(t.Line)
+
+		if t.Line != lastLine {
+			lastLine = t.Line
+			lineCursor = len(t.Previous)
+		}
+
+		pos := start + lineCursor
+		if pos > end {
+			pos = end
+		}
+		needle := strings.ToLower(t.Text)
+		idx := bytes.Index(lower[pos:end], []byte(needle))
+		if idx < 0 {
+			offsets[i] = pos
+			continue
+		}
+		offsets[i] = pos + idx
+		lineCursor = (offsets[i] - start) + len(needle)
+	}
+	return offsets, lineEnd
+}
+
+// tokenEndOffset returns the byte offset in raw one past the end of
+// tokens[i]'s span. It does not use len(tokens[i].Text), since Text is
+// normalized and a lossy Tokenizer (e.g. one that strips trailing
+// punctuation or diacritics) can make it shorter than the token's real
+// source span. If another token follows on the same line, the span is
+// everything up to that token's start, which recovers any such stripped
+// trailing characters; otherwise the span runs to the end of the token's
+// line (or the end of raw, for the document's last line).
+func tokenEndOffset(raw []byte, lineEnd func(line int) int, tokens []*token, offsets []int, i int) int {
+	if i+1 < len(tokens) && tokens[i+1].Line == tokens[i].Line {
+		return offsets[i+1]
+	}
+	return lineEnd(tokens[i].Line)
+}
+
+// runeColumn returns the 1-based rune column of byte offset pos within its
+// line of raw.
+func runeColumn(raw []byte, pos int) int {
+	if pos > len(raw) {
+		pos = len(raw)
+	}
+	lineStart := bytes.LastIndexByte(raw[:pos], '\n') + 1
+	return len([]rune(string(raw[lineStart:pos]))) + 1
+}
@@ -0,0 +1,104 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import "testing"
+
+func TestMatchSnippetAndPositions(t *testing.T) {
+	c := NewClassifier(DefaultConfidenceThreshold)
+	raw := []byte("Copyright 2020 Example Inc.\nLicensed under the Apache License.")
+	target := c.createTargetIndexedDocument(raw)
+
+	m := &Match{target: target, startTokenIndex: 0, endTokenIndex: 2}
+
+	positions := m.Positions()
+	if len(positions) != 1 {
+		t.Fatalf("Positions: got %d ranges, want 1", len(positions))
+	}
+	if positions[0].StartLine != 1 {
+		t.Errorf("Positions: StartLine = %d, want 1", positions[0].StartLine)
+	}
+	if positions[0].StartCol != 1 {
+		t.Errorf("Positions: StartCol = %d, want 1", positions[0].StartCol)
+	}
+
+	// The match's span runs up to the start of the next token ("Example"),
+	// so it includes the separating space rather than stopping exactly at
+	// the end of "2020" - see tokenEndOffset.
+	snippet := m.Snippet(0)
+	if snippet != "Copyright 2020 " {
+		t.Errorf("Snippet(0) = %q, want %q", snippet, "Copyright 2020 ")
+	}
+}
+
+// TestMatchSnippetRecoversStrippedTrailingPunctuation exercises a lossy
+// Tokenizer whose normalized Text drops a token's trailing punctuation. The
+// old end-bound (offset + len(last.Text)) would truncate the snippet before
+// that punctuation; deriving it from the next token's start instead should
+// recover it.
+func TestMatchSnippetRecoversStrippedTrailingPunctuation(t *testing.T) {
+	lossy := constTokenizer{doc: &document{Tokens: []*token{
+		{Text: "example", Index: 0, Line: 1},
+		{Text: "inc", Index: 1, Line: 1}, // raw text is "Inc.", trailing "." stripped
+		{Text: "all", Index: 2, Line: 1},
+		{Text: "rights", Index: 3, Line: 1},
+	}}}
+	c := NewClassifier(DefaultConfidenceThreshold, WithTokenizer(lossy))
+	target := c.createTargetIndexedDocument([]byte("Example Inc. All rights"))
+
+	m := &Match{target: target, startTokenIndex: 0, endTokenIndex: 2}
+	if got, want := m.Snippet(0), "Example Inc. "; got != want {
+		t.Errorf("Snippet(0) = %q, want %q (trailing period from the lossy tokenizer's stripped text)", got, want)
+	}
+}
+
+func TestMatchSnippetSecondLineResync(t *testing.T) {
+	c := NewClassifier(DefaultConfidenceThreshold)
+	raw := []byte("Copyright 2020 Example Inc.\nLicensed under the Apache License.")
+	target := c.createTargetIndexedDocument(raw)
+
+	var secondLineStart int
+	for i, tok := range target.srcTokens {
+		if tok.Line == 2 {
+			secondLineStart = i
+			break
+		}
+	}
+
+	m := &Match{target: target, startTokenIndex: secondLineStart, endTokenIndex: secondLineStart + 1}
+	positions := m.Positions()
+	if len(positions) != 1 {
+		t.Fatalf("Positions: got %d ranges, want 1", len(positions))
+	}
+	if positions[0].StartLine != 2 {
+		t.Errorf("Positions: StartLine = %d, want 2 (resync from the new line's own Previous/Line)", positions[0].StartLine)
+	}
+	if positions[0].StartCol != 1 {
+		t.Errorf("Positions: StartCol = %d, want 1 (offset search restarts at the new line)", positions[0].StartCol)
+	}
+}
+
+func TestMatchPositionsEmptyForTokenStreamsWithoutSource(t *testing.T) {
+	c := NewClassifier(DefaultConfidenceThreshold)
+	target := c.MatchTokens([]*token{{Text: "word", Index: 0, Line: 1}})
+
+	m := &Match{target: target, startTokenIndex: 0, endTokenIndex: 1}
+	if got := m.Positions(); got != nil {
+		t.Errorf("Positions: got %v, want nil when the target has no raw source bytes", got)
+	}
+	if got := m.Snippet(5); got != "" {
+		t.Errorf("Snippet: got %q, want empty when the target has no raw source bytes", got)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+// Tokenizer turns raw content into a stream of tokens. The default
+// tokenization, used when a Classifier has no Tokenizer configured, is the
+// package-level tokenize function, which assumes whitespace-delimited
+// English-language text. Callers with CJK license notices, markup formats
+// such as RST or AsciiDoc, or license text extracted from source-code
+// comments can supply their own Tokenizer via WithTokenizer to control
+// segmentation and normalization themselves.
+type Tokenizer interface {
+	Tokenize(content []byte) *document
+}
+
+// TokenizerFunc adapts a plain function to the Tokenizer interface, the way
+// http.HandlerFunc adapts a function to http.Handler.
+type TokenizerFunc func(content []byte) *document
+
+// Tokenize calls f.
+func (f TokenizerFunc) Tokenize(content []byte) *document {
+	return f(content)
+}
+
+// WithTokenizer configures a Classifier to use t instead of the default
+// tokenizer for every subsequent AddContent and matching call. It does not
+// affect documents already added to the classifier.
+func WithTokenizer(t Tokenizer) Option {
+	return func(c *Classifier) {
+		c.tokenizer = t
+	}
+}
+
+// WithTokenizerFunc is a convenience over WithTokenizer for callers who want
+// to supply a plain function instead of implementing the Tokenizer
+// interface themselves.
+func WithTokenizerFunc(f func(content []byte) *document) Option {
+	return WithTokenizer(TokenizerFunc(f))
+}
+
+// tokenizeContent runs content through the classifier's configured Tokenizer, or
+// the package default if none was set via WithTokenizer.
+func (c *Classifier) tokenizeContent(content []byte) *document {
+	if c.tokenizer != nil {
+		return c.tokenizer.Tokenize(content)
+	}
+	return tokenize(content)
+}
+
+// AddContentTokens incorporates an already-tokenized document into the
+// classifier for matching, bypassing both the default tokenizer and any
+// Tokenizer configured with WithTokenizer. This lets callers share a single
+// tokenization pass between file-scanning and matching, or feed in
+// pre-segmented input (e.g. from a CJK segmenter) without implementing the
+// Tokenizer interface.
+func (c *Classifier) AddContentTokens(category, name, variant string, tokens []*token) {
+	c.addDocument(category, name, variant, &document{Tokens: tokens})
+}
+
+// MatchTokens is the Tokenizer-bypassing counterpart to createTargetIndexedDocument:
+// it builds a target IndexedDocument directly from an already-tokenized
+// stream, without adding its words to the classifier's dictionary.
+func (c *Classifier) MatchTokens(tokens []*token) *IndexedDocument {
+	id := c.generateIndexedDocument(&document{Tokens: tokens}, false)
+	id.srcTokens = tokens
+	return id
+}
@@ -0,0 +1,82 @@
+// Copyright 2020 Google Inc.
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package classifier
+
+import "testing"
+
+// constTokenizer always returns the same pre-segmented document, regardless
+// of its input content, so tests can tell whether it or the package default
+// tokenizer ran.
+type constTokenizer struct {
+	doc *document
+}
+
+func (c constTokenizer) Tokenize(content []byte) *document {
+	return c.doc
+}
+
+func TestWithTokenizerOverridesDefault(t *testing.T) {
+	want := &document{Tokens: []*token{
+		{Text: "custom", Index: 0, Line: 1},
+		{Text: "segmentation", Index: 1, Line: 1},
+	}}
+	c := NewClassifier(DefaultConfidenceThreshold, WithTokenizer(constTokenizer{doc: want}))
+
+	c.AddContent("license", "Custom", "Custom", []byte("this content is ignored by constTokenizer"))
+
+	doc := c.getIndexedDocument("license", "Custom", "Custom")
+	if doc == nil {
+		t.Fatal("AddContent: document not found after add")
+	}
+	if got := doc.size(); got != len(want.Tokens) {
+		t.Fatalf("AddContent: got %d tokens, want %d (WithTokenizer was not used)", got, len(want.Tokens))
+	}
+}
+
+func TestWithTokenizerFunc(t *testing.T) {
+	want := &document{Tokens: []*token{{Text: "func-based", Index: 0, Line: 1}}}
+	c := NewClassifier(DefaultConfidenceThreshold, WithTokenizerFunc(func(content []byte) *document {
+		return want
+	}))
+
+	c.AddContent("license", "Custom", "Custom", []byte("ignored"))
+
+	doc := c.getIndexedDocument("license", "Custom", "Custom")
+	if doc == nil || doc.size() != len(want.Tokens) {
+		t.Fatalf("AddContent: document missing or wrong size (WithTokenizerFunc was not used)")
+	}
+}
+
+func TestAddContentTokensAndMatchTokens(t *testing.T) {
+	c := NewClassifier(DefaultConfidenceThreshold)
+	toks := []*token{
+		{Text: "pre", Index: 0, Line: 1},
+		{Text: "segmented", Index: 1, Line: 1},
+	}
+
+	c.AddContentTokens("license", "PreSeg", "PreSeg", toks)
+	corpusDoc := c.getIndexedDocument("license", "PreSeg", "PreSeg")
+	if corpusDoc == nil || corpusDoc.size() != len(toks) {
+		t.Fatalf("AddContentTokens: document missing or wrong size")
+	}
+
+	target := c.MatchTokens(toks)
+	if target.size() != len(toks) {
+		t.Fatalf("MatchTokens: got %d tokens, want %d", target.size(), len(toks))
+	}
+	if _, ok := c.Docs["does-not-exist"]; ok {
+		t.Fatal("MatchTokens: must not add the target to the corpus")
+	}
+}